@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/scribble-rs/scribble.rs/internal/game"
+)
+
+// LobbyByID looks up a currently running lobby by its ID, returning false
+// if no such lobby exists. It is implemented by whatever keeps the
+// in-memory registry of lobbies.
+type LobbyByID func(id string) (*game.Lobby, bool)
+
+// registerBotRequest is the body expected by RegisterBotHandler. OwnerSession
+// and ReconnectToken identify the calling owner the same way a websocket
+// reconnect does, see game.Lobby.AuthenticateOwner.
+type registerBotRequest struct {
+	Name           string    `json:"name"`
+	Endpoint       string    `json:"endpoint"`
+	AuthToken      string    `json:"authToken"`
+	OwnerSession   uuid.UUID `json:"ownerSession"`
+	ReconnectToken string    `json:"reconnectToken"`
+}
+
+// RegisterBotHandler returns a handler for registering a bot on a lobby,
+// expected to be mounted at POST /v1/lobby/bot?lobbyId={id}.
+func RegisterBotHandler(lobbyByID LobbyByID) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		lobbyID := request.URL.Query().Get("lobbyId")
+		lobby, ok := lobbyByID(lobbyID)
+		if !ok {
+			http.Error(writer, "lobby not found", http.StatusNotFound)
+			return
+		}
+
+		var body registerBotRequest
+		if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+			http.Error(writer, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if !lobby.AuthenticateOwner(body.OwnerSession, body.ReconnectToken) {
+			http.Error(writer, "only the lobby owner may register a bot", http.StatusForbidden)
+			return
+		}
+
+		bot, err := lobby.RegisterBot(body.Name, body.Endpoint, body.AuthToken)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusConflict)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(bot)
+	}
+}
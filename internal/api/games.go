@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/scribble-rs/scribble.rs/internal/game"
+)
+
+// ListLobbies returns every currently running lobby. It is implemented by
+// whatever keeps the in-memory registry of lobbies.
+type ListLobbies func() []*game.Lobby
+
+// ListGamesHandler returns a handler for lobby discovery, listing every
+// public lobby as a GameInfo. It is expected to be mounted at GET
+// /v1/games.
+func ListGamesHandler(listLobbies ListLobbies) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		infos := make([]*game.GameInfo, 0)
+		for _, lobby := range listLobbies() {
+			if !lobby.Public {
+				continue
+			}
+			infos = append(infos, lobby.GameInfo())
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(infos)
+	}
+}
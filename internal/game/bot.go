@@ -0,0 +1,247 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// defaultBotHTTPTimeout is the maximum amount of time the scheduler waits
+// for a bot to respond to a draw or guess request, before giving up on the
+// bot for that turn.
+const defaultBotHTTPTimeout = 10 * time.Second
+
+// defaultBotCadence is used for a BotPlayer whose Cadence hasn't been set.
+const defaultBotCadence = 700 * time.Millisecond
+
+// maxBotResponseBytes caps how much of a bot's HTTP response is read, so a
+// misbehaving or malicious bot endpoint can't exhaust server memory by
+// returning an unbounded body.
+const maxBotResponseBytes = 1 << 20 // 1 MiB
+
+// defaultBotCanvasWidth and defaultBotCanvasHeight are the canvas
+// dimensions reported to a bot asked to draw, matching the fixed size the
+// client-side canvas renders at.
+const (
+	defaultBotCanvasWidth  = 800
+	defaultBotCanvasHeight = 600
+)
+
+// BotScheduler multiplexes bot turns on behalf of a Lobby, without blocking
+// the main game loop. Every call returns immediately; the actual HTTP
+// roundtrip and event replay happens on a dedicated goroutine per bot turn.
+type BotScheduler struct {
+	client *http.Client
+	// onEvent is invoked on the scheduler's goroutine for every line or fill
+	// event replayed out of a bot's drawing response. The caller is
+	// expected to broadcast it to the lobby the same way it would a human
+	// player's event.
+	onEvent func(event any)
+	// onGuess is invoked on the scheduler's goroutine whenever a bot's
+	// guess response contains a non-empty guess.
+	onGuess func(bot *BotPlayer, guess string)
+}
+
+// NewBotScheduler creates a BotScheduler that reports replayed drawing
+// events via onEvent and bot guesses via onGuess.
+func NewBotScheduler(onEvent func(event any), onGuess func(bot *BotPlayer, guess string)) *BotScheduler {
+	return &BotScheduler{
+		client: &http.Client{
+			Timeout: defaultBotHTTPTimeout,
+			// Bot endpoints are validated once at registration time, see
+			// validateBotEndpoint. Following a redirect would let a bot
+			// endpoint that passed that check send the request (including
+			// the Authorization header) anywhere it likes, including
+			// loopback or cloud-metadata addresses, bypassing the check
+			// entirely. Refusing to follow closes that off.
+			CheckRedirect: func(request *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		onEvent: onEvent,
+		onGuess: onGuess,
+	}
+}
+
+// ScheduleDrawTurn asynchronously requests a drawing from the bot and
+// replays the returned events at the bot's configured Cadence. It returns
+// immediately; errors are swallowed into a single system-message-worthy
+// string logged by the caller via the returned channel, which is closed
+// once the bot's turn has finished replaying.
+func (s *BotScheduler) ScheduleDrawTurn(bot *BotPlayer, word string, width, height int) <-chan error {
+	done := make(chan error, 1)
+
+	go func() {
+		defer close(done)
+
+		response, err := s.postBot(bot, "/draw", &BotDrawRequest{
+			Word:   word,
+			Width:  width,
+			Height: height,
+		})
+		if err != nil {
+			done <- fmt.Errorf("requesting drawing from bot %s: %w", bot.Name, err)
+			return
+		}
+
+		var drawResponse BotDrawResponse
+		if err := json.Unmarshal(response, &drawResponse); err != nil {
+			done <- fmt.Errorf("decoding drawing response from bot %s: %w", bot.Name, err)
+			return
+		}
+
+		cadence := bot.Cadence
+		if cadence <= 0 {
+			cadence = defaultBotCadence
+		}
+
+		for _, raw := range drawResponse.Events {
+			event, err := decodeBotEvent(raw)
+			if err != nil {
+				done <- fmt.Errorf("decoding event from bot %s: %w", bot.Name, err)
+				return
+			}
+			s.onEvent(event)
+			time.Sleep(cadence)
+		}
+	}()
+
+	return done
+}
+
+// ScheduleGuess asynchronously requests a guess from the bot for the
+// current drawing state. It returns immediately; a non-empty guess is
+// reported via onGuess once the bot has responded.
+func (s *BotScheduler) ScheduleGuess(bot *BotPlayer, wordHints []*WordHint, events []any) <-chan error {
+	done := make(chan error, 1)
+
+	go func() {
+		defer close(done)
+
+		response, err := s.postBot(bot, "/guess", &BotGuessRequest{
+			WordHints: wordHints,
+			Events:    events,
+		})
+		if err != nil {
+			done <- fmt.Errorf("requesting guess from bot %s: %w", bot.Name, err)
+			return
+		}
+
+		var guessResponse BotGuessResponse
+		if err := json.Unmarshal(response, &guessResponse); err != nil {
+			done <- fmt.Errorf("decoding guess response from bot %s: %w", bot.Name, err)
+			return
+		}
+
+		if guessResponse.Guess != "" {
+			s.onGuess(bot, guessResponse.Guess)
+		}
+	}()
+
+	return done
+}
+
+// decodeBotEvent decodes a single event from a bot's draw response into its
+// concrete LineEvent or FillEvent type, keyed off its "type" field. Without
+// this, json.Unmarshal into []any would hand callers a plain
+// map[string]interface{} that can never match a LineEvent/FillEvent type
+// switch.
+func decodeBotEvent(raw json.RawMessage) (any, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("reading event type: %w", err)
+	}
+
+	switch header.Type {
+	case EventTypeLine:
+		var event LineEvent
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return nil, fmt.Errorf("decoding line event: %w", err)
+		}
+		return event, nil
+	case EventTypeFill:
+		var event FillEvent
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return nil, fmt.Errorf("decoding fill event: %w", err)
+		}
+		return event, nil
+	default:
+		return nil, fmt.Errorf("unknown event type %q", header.Type)
+	}
+}
+
+// validateBotEndpoint rejects bot endpoints that point at loopback, private
+// or link-local addresses, so that registering a bot can't be used to make
+// the server issue authenticated-looking POST requests into internal
+// infrastructure (SSRF). This isn't a complete defense against DNS
+// rebinding, since the resolved address can change between this check and
+// the actual request, but it closes the common case of a literal internal
+// URL.
+func validateBotEndpoint(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parsing endpoint: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("endpoint must use http or https")
+	}
+
+	host := parsed.Hostname()
+	if host == "" {
+		return fmt.Errorf("endpoint must have a host")
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("resolving endpoint host: %w", err)
+	}
+	for _, ip := range ips {
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+			return fmt.Errorf("endpoint resolves to a disallowed address")
+		}
+	}
+
+	return nil
+}
+
+// postBot sends payload as JSON to bot.Endpoint+path, authenticating via
+// bot.AuthToken, and returns the raw response body.
+func (s *BotScheduler) postBot(bot *BotPlayer, path string, payload any) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request body: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, bot.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if bot.AuthToken != "" {
+		request.Header.Set("Authorization", "Bearer "+bot.AuthToken)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("performing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bot endpoint returned status %d", response.StatusCode)
+	}
+
+	buffer := new(bytes.Buffer)
+	if _, err := buffer.ReadFrom(io.LimitReader(response.Body, maxBotResponseBytes)); err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
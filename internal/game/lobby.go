@@ -0,0 +1,314 @@
+package game
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Lobby is a running game instance: its players, spectators, bots and
+// current round state, plus the settings it was configured with. It ties
+// together the turn flow, bot scheduling, spectator bootstrap and
+// reconnection handling that would otherwise just be a pile of disjoint
+// helpers.
+type Lobby struct {
+	mutex sync.Mutex
+
+	ID string
+	EditableLobbySettings
+
+	CreatedAt time.Time
+	State     State
+	OwnerID   uuid.UUID
+
+	Players    []*Player
+	Spectators []*Spectator
+	Bots       []*BotPlayer
+
+	Round int
+
+	CurrentWord string
+	WordHints   []*WordHint
+	DrawerID    uuid.UUID
+
+	drawingHistory DrawingHistory
+	botScheduler   *BotScheduler
+
+	// reconnectSecret is used to issue and validate ReconnectTokens for
+	// this lobby's players.
+	reconnectSecret []byte
+
+	turnReviews []*TurnReviewEvent
+	timeSync    *TimeSyncTicker
+
+	// chooseWordDeadline and drawingDeadline are the unix-ms deadlines of
+	// the most recent choose-word and drawing phases, respectively. Each
+	// keeps its value after its phase ends; buildReadyLocked reports
+	// drawingDeadline as Ready.RoundEndTime whichever phase is active.
+	chooseWordDeadline int64
+	drawingDeadline    int64
+	// turnStartedAt is the unix-ms time the current drawing phase began,
+	// used by FinishTurn to compute DrawingDurationMs for the turn's
+	// TurnReviewEvent.
+	turnStartedAt int64
+}
+
+// NewLobby creates a ready-to-use, empty Lobby.
+func NewLobby(id string, settings EditableLobbySettings, ownerID uuid.UUID, reconnectSecret []byte) *Lobby {
+	return &Lobby{
+		ID:                    id,
+		EditableLobbySettings: settings,
+		CreatedAt:             time.Now(),
+		State:                 Unstarted,
+		OwnerID:               ownerID,
+		reconnectSecret:       reconnectSecret,
+	}
+}
+
+// Broadcast dispatches event to every connection that its Audience allows.
+// AudienceGame and AudienceLobby reach both players and spectators;
+// AudienceSpectator reaches only spectators.
+func (l *Lobby) Broadcast(event *Event) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if event.Audience != AudienceSpectator {
+		for _, player := range l.Players {
+			_ = player.WriteJSON(event)
+		}
+	}
+
+	for _, spectator := range l.Spectators {
+		_ = spectator.WriteJSON(event)
+	}
+}
+
+// RegisterBot registers a new bot for this lobby, to be invoked over HTTP
+// whenever it's the bot's turn to draw or another player is drawing. It is
+// the implementation backing the bot registration REST endpoint, see
+// api.RegisterBotHandler.
+func (l *Lobby) RegisterBot(name, endpoint, authToken string) (*BotPlayer, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.Bots) >= l.BotSlots {
+		return nil, fmt.Errorf("lobby %s has no free bot slots", l.ID)
+	}
+
+	if err := validateBotEndpoint(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid bot endpoint: %w", err)
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, fmt.Errorf("generating bot id: %w", err)
+	}
+
+	bot := &BotPlayer{
+		ID:        id,
+		Name:      name,
+		Endpoint:  endpoint,
+		AuthToken: authToken,
+		Cadence:   defaultBotCadence,
+	}
+
+	if l.botScheduler == nil {
+		l.botScheduler = NewBotScheduler(l.handleBotDrawingEvent, l.handleBotGuess)
+	}
+
+	l.Bots = append(l.Bots, bot)
+	l.BotEndpoints = append(l.BotEndpoints, endpoint)
+
+	return bot, nil
+}
+
+// botByIDLocked returns the BotPlayer with the given id, or nil if id
+// doesn't belong to a registered bot. l.mutex must be held.
+func (l *Lobby) botByIDLocked(id uuid.UUID) *BotPlayer {
+	for _, bot := range l.Bots {
+		if bot.ID == id {
+			return bot
+		}
+	}
+	return nil
+}
+
+// StartBotDrawingTurn asks bot to draw word on a width x height canvas and
+// replays its response, then asks every other bot to guess once drawing
+// events start arriving. It returns immediately; the turn finishes
+// asynchronously on the scheduler's goroutine.
+func (l *Lobby) StartBotDrawingTurn(bot *BotPlayer, word string, width, height int) {
+	l.mutex.Lock()
+	l.DrawerID = bot.ID
+	l.CurrentWord = word
+	l.mutex.Unlock()
+
+	go func() {
+		for err := range l.botScheduler.ScheduleDrawTurn(bot, word, width, height) {
+			if err != nil {
+				l.Broadcast(&Event{
+					Type: EventTypeSystemMessage,
+					Data: fmt.Sprintf("bot %s failed to draw: %s", bot.Name, err),
+				})
+			}
+		}
+	}()
+}
+
+// RequestBotGuesses asks every registered bot other than the current
+// drawer to guess the word, given the wordhints and drawing events
+// revealed so far. Each bot is asked independently and concurrently; a
+// guess is broadcast as a chat message as soon as it comes back.
+func (l *Lobby) RequestBotGuesses(wordHints []*WordHint, events []any) {
+	l.mutex.Lock()
+	bots := make([]*BotPlayer, 0, len(l.Bots))
+	for _, bot := range l.Bots {
+		if bot.ID != l.DrawerID {
+			bots = append(bots, bot)
+		}
+	}
+	scheduler := l.botScheduler
+	l.mutex.Unlock()
+
+	if scheduler == nil {
+		return
+	}
+
+	for _, bot := range bots {
+		go func(bot *BotPlayer) {
+			for err := range scheduler.ScheduleGuess(bot, wordHints, events) {
+				if err != nil {
+					l.Broadcast(&Event{
+						Type: EventTypeSystemMessage,
+						Data: fmt.Sprintf("bot %s failed to guess: %s", bot.Name, err),
+					})
+				}
+			}
+		}(bot)
+	}
+}
+
+// handleBotDrawingEvent is invoked by the BotScheduler for every line or
+// fill event replayed out of a bot's drawing response, already decoded into
+// its concrete type by decodeBotEvent. It records line strokes in the
+// drawing history, broadcasts the event under its proper type, and asks the
+// other bots to guess based on it, the same way a human player's stroke
+// would feed into the turn flow.
+func (l *Lobby) handleBotDrawingEvent(event any) {
+	var eventType string
+
+	l.mutex.Lock()
+	switch typed := event.(type) {
+	case LineEvent:
+		eventType = EventTypeLine
+		_ = l.drawingHistory.Append(typed)
+	case FillEvent:
+		eventType = EventTypeFill
+		l.drawingHistory.AppendFill(typed)
+	}
+	wordHints := l.WordHints
+	l.mutex.Unlock()
+
+	l.Broadcast(&Event{Type: eventType, Data: event})
+	l.RequestBotGuesses(wordHints, []any{event})
+}
+
+// handleBotGuess is invoked by the BotScheduler whenever a bot responds
+// with a non-empty guess, and injects it into the lobby's chat the same
+// way a human player's guess message would be.
+func (l *Lobby) handleBotGuess(bot *BotPlayer, guess string) {
+	l.Broadcast(&Event{
+		Type: EventTypeMessage,
+		Data: &OutgoingMessage{
+			Author:   bot.Name,
+			AuthorID: bot.ID,
+			Content:  guess,
+		},
+	})
+}
+
+// IssueReconnectTokenFor computes and stores this lobby's ReconnectToken for
+// player, to be handed back to the client at first join. Presenting the
+// token on a later connection is what lets CanJoin authenticate a returning
+// player instead of trusting their spoofable display name.
+func (l *Lobby) IssueReconnectTokenFor(player *Player) string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	token := IssueReconnectToken(l.reconnectSecret, player.userSession, l.ID)
+	player.reconnectToken = token
+	player.lastSeen = time.Now()
+	return token
+}
+
+// GameInfo returns a stable, persistable summary of the lobby, see GameInfo,
+// suitable for a lobby-discovery listing endpoint.
+func (l *Lobby) GameInfo() *GameInfo {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	names := make([]string, 0, len(l.Players))
+	for _, player := range l.Players {
+		names = append(names, player.Name)
+	}
+
+	return &GameInfo{
+		ID:          l.ID,
+		Status:      l.State,
+		PlayerNames: names,
+		CreatedAt:   l.CreatedAt.UnixMilli(),
+		Round:       l.Round,
+		Rounds:      l.Rounds,
+		// HasPassword is always false for now; this lobby has no password
+		// feature yet for GameInfo to reflect.
+		HasPassword: false,
+	}
+}
+
+// AddSpectator registers a new spectator connection, broadcasts its join to
+// the lobby, the new spectator included, and returns the bootstrap payload the spectator
+// itself needs to replay the current game state. supportsDrawingSnapshot is
+// as in BuildReady.
+func (l *Lobby) AddSpectator(spectator *Spectator, supportsDrawingSnapshot bool) *SpectatorReady {
+	l.mutex.Lock()
+	l.Spectators = append(l.Spectators, spectator)
+	l.mutex.Unlock()
+
+	l.Broadcast(&Event{
+		Type:     EventTypeSpectatorJoin,
+		Data:     spectator.AsPlayer(),
+		Audience: AudienceLobby,
+	})
+
+	return l.BuildSpectatorReady(spectator, supportsDrawingSnapshot)
+}
+
+// RemoveSpectator unregisters spectator and broadcasts its departure to the
+// rest of the lobby. It is a no-op if spectator had already been removed.
+func (l *Lobby) RemoveSpectator(spectator *Spectator) {
+	l.mutex.Lock()
+	for i, s := range l.Spectators {
+		if s == spectator {
+			l.Spectators = append(l.Spectators[:i], l.Spectators[i+1:]...)
+			break
+		}
+	}
+	l.mutex.Unlock()
+
+	l.Broadcast(&Event{
+		Type:     EventTypeSpectatorLeave,
+		Data:     spectator.AsPlayer(),
+		Audience: AudienceLobby,
+	})
+}
+
+// WriteJSON sends data as a JSON encoded websocket message, guarding
+// against concurrent writes the same way Spectator's connection handling
+// does.
+func (player *Player) WriteJSON(data any) error {
+	player.socketMutex.Lock()
+	defer player.socketMutex.Unlock()
+	return player.ws.WriteJSON(data)
+}
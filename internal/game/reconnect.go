@@ -0,0 +1,68 @@
+package game
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+
+	"github.com/gofrs/uuid"
+)
+
+// IssueReconnectToken computes a stable ReconnectToken for a player, derived
+// from their userSession and the lobby they joined. Presenting this token
+// over the websocket query-string on a later connection lets the player
+// resume the same Player slot, restoring Score, Rank, votedForKick and
+// desiredState after a crash or page close.
+func IssueReconnectToken(secret []byte, userSession uuid.UUID, lobbyID string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(userSession.Bytes())
+	mac.Write([]byte(lobbyID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateReconnectToken reports whether token was indeed issued for the
+// given userSession and lobbyID, using a constant-time comparison to avoid
+// leaking timing information about the expected token.
+func ValidateReconnectToken(secret []byte, userSession uuid.UUID, lobbyID string, token string) bool {
+	expected := IssueReconnectToken(secret, userSession, lobbyID)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
+}
+
+// CanJoin decides whether the player behind userSession, presenting
+// reconnectToken, is allowed to (re)join a lobby with id lobbyID that
+// already has the given players, maxPlayers capacity and state. A returning
+// player is recognized by userSession, never by the spoofable display name
+// they present, and is only let back into their existing slot once
+// reconnectToken validates against secret for that exact session and lobby;
+// this is allowed even if the lobby is at capacity or already Ongoing,
+// since they're resuming their own slot rather than taking a new one. A
+// session that isn't already seated can only take a brand-new slot, which
+// requires both a free slot and the lobby not having started yet; once a
+// lobby is Ongoing, it no longer accepts new, never-before-seen players.
+func CanJoin(players []*Player, userSession uuid.UUID, reconnectToken string, secret []byte, lobbyID string, maxPlayers int, state State) bool {
+	for _, player := range players {
+		if player.userSession != userSession {
+			continue
+		}
+		return reconnectToken != "" && ValidateReconnectToken(secret, userSession, lobbyID, reconnectToken)
+	}
+
+	if state == Ongoing {
+		return false
+	}
+
+	return len(players) < maxPlayers
+}
+
+// AuthenticateOwner reports whether userSession, presenting reconnectToken,
+// is this lobby's owner. It gates owner-only lobby actions, such as
+// registering a bot, the same way CanJoin authenticates a returning player.
+func (l *Lobby) AuthenticateOwner(userSession uuid.UUID, reconnectToken string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return userSession == l.OwnerID &&
+		reconnectToken != "" &&
+		ValidateReconnectToken(l.reconnectSecret, userSession, l.ID, reconnectToken)
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestCanJoin(t *testing.T) {
+	secret := []byte("test-secret")
+	lobbyID := "test-lobby"
+
+	existingSession := uuid.Must(uuid.NewV4())
+	existingPlayer := &Player{userSession: existingSession}
+	validToken := IssueReconnectToken(secret, existingSession, lobbyID)
+
+	players := []*Player{existingPlayer}
+
+	t.Run("returning player with a valid token is always allowed back in, even at capacity and ongoing", func(t *testing.T) {
+		if !CanJoin(players, existingSession, validToken, secret, lobbyID, 1, Ongoing) {
+			t.Fatal("expected returning player with a valid token to be allowed in")
+		}
+	})
+
+	t.Run("returning session with a forged token is rejected", func(t *testing.T) {
+		if CanJoin(players, existingSession, "not-the-real-token", secret, lobbyID, 1, Ongoing) {
+			t.Fatal("expected a forged token to be rejected")
+		}
+	})
+
+	t.Run("returning session with no token is rejected", func(t *testing.T) {
+		if CanJoin(players, existingSession, "", secret, lobbyID, 1, Ongoing) {
+			t.Fatal("expected a missing token to be rejected")
+		}
+	})
+
+	t.Run("token issued for a different lobby is rejected", func(t *testing.T) {
+		otherLobbyToken := IssueReconnectToken(secret, existingSession, "other-lobby")
+		if CanJoin(players, existingSession, otherLobbyToken, secret, lobbyID, 1, Ongoing) {
+			t.Fatal("expected a token issued for another lobby to be rejected")
+		}
+	})
+
+	t.Run("new session is allowed in under capacity", func(t *testing.T) {
+		newSession := uuid.Must(uuid.NewV4())
+		if !CanJoin(players, newSession, "", secret, lobbyID, 2, Unstarted) {
+			t.Fatal("expected a new session to be allowed in under capacity")
+		}
+	})
+
+	t.Run("new session is rejected once the lobby is ongoing, even under capacity", func(t *testing.T) {
+		newSession := uuid.Must(uuid.NewV4())
+		if CanJoin(players, newSession, "", secret, lobbyID, 2, Ongoing) {
+			t.Fatal("expected a new session to be rejected once the lobby is ongoing")
+		}
+	})
+
+	t.Run("new session is rejected once the lobby is full and ongoing", func(t *testing.T) {
+		newSession := uuid.Must(uuid.NewV4())
+		if CanJoin(players, newSession, "", secret, lobbyID, 1, Ongoing) {
+			t.Fatal("expected a new session to be rejected once the lobby is full and ongoing")
+		}
+	})
+
+	t.Run("new session is rejected once MaxPlayers is reached regardless of state", func(t *testing.T) {
+		newSession := uuid.Must(uuid.NewV4())
+		if CanJoin(players, newSession, "", secret, lobbyID, 1, Unstarted) {
+			t.Fatal("expected a new session to be rejected once MaxPlayers is reached")
+		}
+	})
+}
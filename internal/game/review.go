@@ -0,0 +1,151 @@
+package game
+
+// closeGuessMaxDistance is the maximum Levenshtein distance a wrong guess
+// may have from the word to still be considered "close" in a TurnReviewEvent.
+const closeGuessMaxDistance = 2
+
+// excessiveUndoThreshold is the amount of undo uses within a single turn
+// that's flagged as excessive in a TurnReviewEvent.
+const excessiveUndoThreshold = 5
+
+// GuessRecord is a single guess made during a turn, used as input for
+// BuildTurnReview.
+type GuessRecord struct {
+	Player        *Player
+	Guess         string
+	Correct       bool
+	TimeToGuessMs int64
+	ScoreDelta    int
+}
+
+// BuildTurnReview summarizes a finished turn into a TurnReviewEvent and
+// updates the accumulated per-player stats used later by BuildGameReview.
+// undoCount is how many times the drawer used undo during this turn.
+func BuildTurnReview(word string, drawer *Player, drawingDurationMs int64, guesses []GuessRecord, undoCount int) *TurnReviewEvent {
+	review := &TurnReviewEvent{
+		Word:              word,
+		DrawerID:          drawer.ID,
+		DrawerName:        drawer.Name,
+		ExcessiveUndo:     undoCount >= excessiveUndoThreshold,
+		DrawingDurationMs: drawingDurationMs,
+	}
+
+	drawer.turnsAsDrawer++
+	drawer.totalDrawingDurationMs += drawingDurationMs
+	drawer.undoCount += undoCount
+
+	for _, guess := range guesses {
+		stats := &PlayerTurnStats{
+			PlayerID:      guess.Player.ID,
+			PlayerName:    guess.Player.Name,
+			Guessed:       guess.Correct,
+			TimeToGuessMs: guess.TimeToGuessMs,
+			ScoreDelta:    guess.ScoreDelta,
+		}
+		review.PlayerStats = append(review.PlayerStats, stats)
+
+		if guess.Correct {
+			guess.Player.correctGuessCount++
+			guess.Player.totalGuessDurationMs += guess.TimeToGuessMs
+			continue
+		}
+
+		if distance := levenshteinDistance(guess.Guess, word); distance <= closeGuessMaxDistance {
+			guess.Player.closeGuessCount++
+			review.ClosestGuesses = append(review.ClosestGuesses, &CloseGuess{
+				PlayerName: guess.Player.Name,
+				Guess:      guess.Guess,
+				Distance:   distance,
+			})
+		}
+	}
+
+	return review
+}
+
+// BuildGameReview aggregates the turns collected over a game into a
+// GameReview, awarding each player the tags from the fixed GameReviewTag
+// taxonomy that they earned.
+func BuildGameReview(turns []*TurnReviewEvent, players []*Player) *GameReview {
+	review := &GameReview{
+		Turns:      turns,
+		PlayerTags: make(map[string][]GameReviewTag),
+	}
+
+	var fastestGuesser, mostCloseGuesses, slowestDrawer *Player
+
+	for _, player := range players {
+		if player.correctGuessCount > 0 {
+			average := player.totalGuessDurationMs / int64(player.correctGuessCount)
+			if fastestGuesser == nil || average < fastestGuesser.totalGuessDurationMs/int64(fastestGuesser.correctGuessCount) {
+				fastestGuesser = player
+			}
+		}
+
+		if mostCloseGuesses == nil || player.closeGuessCount > mostCloseGuesses.closeGuessCount {
+			mostCloseGuesses = player
+		}
+
+		if player.turnsAsDrawer > 0 {
+			average := player.totalDrawingDurationMs / int64(player.turnsAsDrawer)
+			if slowestDrawer == nil || average > slowestDrawer.totalDrawingDurationMs/int64(slowestDrawer.turnsAsDrawer) {
+				slowestDrawer = player
+			}
+		}
+	}
+
+	addTag := func(player *Player, tag GameReviewTag) {
+		if player == nil {
+			return
+		}
+		id := player.ID.String()
+		review.PlayerTags[id] = append(review.PlayerTags[id], tag)
+	}
+
+	addTag(fastestGuesser, TagFastestGuesser)
+	if mostCloseGuesses != nil && mostCloseGuesses.closeGuessCount > 0 {
+		addTag(mostCloseGuesses, TagMostCloseGuesses)
+	}
+	addTag(slowestDrawer, TagDrawingSpeed)
+
+	return review
+}
+
+// levenshteinDistance computes the edit distance between a and b.
+func levenshteinDistance(a, b string) int {
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+
+	previous := make([]int, len(bRunes)+1)
+	current := make([]int, len(bRunes)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(aRunes); i++ {
+		current[0] = i
+		for j := 1; j <= len(bRunes); j++ {
+			cost := 1
+			if aRunes[i-1] == bRunes[j-1] {
+				cost = 0
+			}
+
+			deletion := previous[j] + 1
+			insertion := current[j-1] + 1
+			substitution := previous[j-1] + cost
+
+			current[j] = min(deletion, min(insertion, substitution))
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(bRunes)]
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"encoding/json"
 	"sync"
 	"time"
 
@@ -41,8 +42,34 @@ const (
 	EventTypeOwnerChange              = "owner-change"
 	EventTypeLobbySettingsChanged     = "lobby-settings-changed"
 	EventTypeShutdown                 = "shutdown"
+	// EventTypeTimeSync is broadcast roughly every 5 seconds while a phase
+	// deadline is active, carrying the server's current time alongside
+	// that deadline. Clients use it to resynchronize their countdowns
+	// without replaying state. It isn't part of the Ready bootstrap; a late
+	// joiner gets the server's time via Ready.ServerNow instead.
+	EventTypeTimeSync = "time-sync"
+	// EventTypeSpectatorJoin is broadcast whenever a spectator connects.
+	EventTypeSpectatorJoin = "spectator-join"
+	// EventTypeSpectatorLeave is broadcast whenever a spectator disconnects.
+	EventTypeSpectatorLeave = "spectator-leave"
+	// EventTypeDrawingSnapshot carries a batched, delta-compressed replay of
+	// the strokes drawn so far, see DrawingSnapshot. It is only sent to
+	// clients that negotiated support for it during the websocket
+	// handshake; older clients keep receiving raw EventTypeLine and
+	// EventTypeFill events instead.
+	EventTypeDrawingSnapshot = "drawing-snapshot"
 )
 
+// EventTypeTurnReview is broadcast right after a turn ends, summarizing it,
+// see TurnReviewEvent.
+const EventTypeTurnReview = "turn-review"
+
+// DrawingSnapshotQueryParam is the websocket handshake query-string
+// parameter a client sets to "true" to indicate it understands
+// EventTypeDrawingSnapshot. Clients that omit it keep receiving the full,
+// uncompressed history of raw line and fill events instead.
+const DrawingSnapshotQueryParam = "supportsDrawingSnapshot"
+
 // Events that are bidirectional.
 var (
 	EventTypeKickVote          = "kick-vote"
@@ -65,10 +92,29 @@ const (
 	GameOver State = "gameOver"
 )
 
+// AudienceType describes which roles an Event is meant for, letting the
+// dispatcher filter which connections actually receive it.
+type AudienceType string
+
+const (
+	// AudienceGame is the default; the event is relevant to drawers and
+	// guessers alike.
+	AudienceGame AudienceType = "game"
+	// AudienceSpectator marks events that are only relevant to spectators.
+	AudienceSpectator AudienceType = "spectator"
+	// AudienceLobby marks events that are relevant regardless of role, for
+	// example lobby settings changes.
+	AudienceLobby AudienceType = "lobby"
+)
+
 // Event contains an eventtype and optionally any data.
 type Event struct {
 	Type string `json:"type"`
 	Data any    `json:"data"`
+	// Audience defines which roles this event is dispatched to. It is
+	// empty for AudienceGame, the default, to avoid bloating the most
+	// common case of event payload.
+	Audience AudienceType `json:"audience,omitempty"`
 }
 
 type StringDataEvent struct {
@@ -119,6 +165,19 @@ type FillEvent struct {
 	Type string `json:"type"`
 }
 
+// DrawingSnapshot batches a run of line and fill strokes into a single,
+// varint delta-compressed binary frame, see EncodeDrawingSnapshot. It is
+// cheaper to send to late joiners than the thousands of individual
+// LineEvent/FillEvent messages it replaces.
+type DrawingSnapshot struct {
+	// StrokeCount is the amount of strokes encoded in Frame;
+	// DecodeDrawingSnapshot decodes exactly this many strokes from Frame.
+	StrokeCount int `json:"strokeCount"`
+	// Frame is the varint delta-compressed encoding of the strokes, see
+	// EncodeDrawingSnapshot.
+	Frame []byte `json:"frame"`
+}
+
 // KickVote represents a players vote to kick another players. If the VoteCount
 // is as great or greater than the RequiredVoteCount, the event indicates a
 // successful kick vote. The voting is anonymous, meaning the voting player
@@ -147,7 +206,66 @@ type NameChangeEvent struct {
 // game is over already.
 type GameOverEvent struct {
 	*Ready
-	PreviousWord string `json:"previousWord"`
+	PreviousWord string      `json:"previousWord"`
+	Review       *GameReview `json:"review"`
+}
+
+// PlayerTurnStats summarizes a single player's performance during one
+// turn, as part of a TurnReviewEvent.
+type PlayerTurnStats struct {
+	PlayerID      uuid.UUID `json:"playerId"`
+	PlayerName    string    `json:"playerName"`
+	Guessed       bool      `json:"guessed"`
+	TimeToGuessMs int64     `json:"timeToGuessMs"`
+	ScoreDelta    int       `json:"scoreDelta"`
+}
+
+// CloseGuess represents a wrong guess that was nonetheless close to the
+// word, measured via Levenshtein distance.
+type CloseGuess struct {
+	PlayerName string `json:"playerName"`
+	Guess      string `json:"guess"`
+	Distance   int    `json:"distance"`
+}
+
+// TurnReviewEvent summarizes a single turn once it has ended, see
+// EventTypeTurnReview.
+type TurnReviewEvent struct {
+	Word              string             `json:"word"`
+	DrawerID          uuid.UUID          `json:"drawerId"`
+	DrawerName        string             `json:"drawerName"`
+	PlayerStats       []*PlayerTurnStats `json:"playerStats"`
+	ClosestGuesses    []*CloseGuess      `json:"closestGuesses"`
+	ExcessiveUndo     bool               `json:"excessiveUndo"`
+	DrawingDurationMs int64              `json:"drawingDurationMs"`
+}
+
+// GameReviewTag is a fixed taxonomy of highlights awarded to players at
+// game over, based on the turn reviews collected over the course of the
+// game.
+type GameReviewTag string
+
+const (
+	// TagFastestGuesser is awarded to the player with the lowest average
+	// time-to-guess across all turns they guessed correctly in.
+	TagFastestGuesser GameReviewTag = "fastestGuesser"
+	// TagMostCloseGuesses is awarded to the player with the most close,
+	// but incorrect, guesses across the game.
+	TagMostCloseGuesses GameReviewTag = "mostCloseGuesses"
+	// TagDrawingSpeed is awarded to the player whose turns as drawer, on
+	// average, lasted the longest, measured from the start of the drawing
+	// phase to the end of the turn.
+	TagDrawingSpeed GameReviewTag = "drawingSpeed"
+)
+
+// GameReview aggregates every TurnReviewEvent collected over the course of
+// a game into a set of per-player tags, sent alongside GameOverEvent.
+type GameReview struct {
+	Turns []*TurnReviewEvent `json:"turns"`
+	// PlayerTags maps a player's ID, as a string, to the tags they earned.
+	// A map keyed by uuid.UUID doesn't marshal to a useful JSON object, so
+	// the string form of the ID is used instead.
+	PlayerTags map[string][]GameReviewTag `json:"playerTags"`
 }
 
 // NextTurn represents the data necessary for displaying the lobby state right
@@ -161,6 +279,48 @@ type NextTurn struct {
 	Players      []*Player `json:"players"`
 	Round        int       `json:"round"`
 	RoundEndTime int       `json:"roundEndTime"`
+	// ServerNow is the server's current unix time in milliseconds, sent
+	// alongside RoundEndTime so that clients can compute the remaining time
+	// themselves instead of trusting a value that drifts on reconnect or
+	// across timezones.
+	ServerNow int64 `json:"serverNow"`
+	// Spectators are the players currently observing the lobby without
+	// being scheduled as drawers.
+	Spectators []*Player `json:"spectators"`
+}
+
+// TimeSyncPhase describes which timer a TimeSyncEvent's Deadline refers to.
+type TimeSyncPhase string
+
+const (
+	// TimeSyncPhaseDrawing means Deadline marks the end of the current
+	// drawing turn.
+	TimeSyncPhaseDrawing TimeSyncPhase = "drawing"
+	// TimeSyncPhaseChooseWord means Deadline marks the end of the drawer's
+	// time to choose a word.
+	TimeSyncPhaseChooseWord TimeSyncPhase = "chooseWord"
+)
+
+// TimeSyncEvent is broadcast periodically, see EventTypeTimeSync, so that
+// clients can resynchronize their local countdown against the server's
+// clock and the deadline of whatever phase is currently active.
+type TimeSyncEvent struct {
+	ServerNow int64         `json:"serverNow"`
+	Deadline  int64         `json:"deadline"`
+	Phase     TimeSyncPhase `json:"phase"`
+}
+
+// YourTurn is sent to the drawer once it's their turn to choose a word. It
+// carries the word-selection deadline, separate from the drawing timer.
+// Nothing in this package acts once that deadline has passed.
+type YourTurn struct {
+	Words    []string `json:"words"`
+	Deadline int64    `json:"deadline"`
+	// ServerNow is the server's current unix time in milliseconds, sent
+	// alongside Deadline for the same reason as on Ready/NextTurn: so
+	// clients can compute the remaining time themselves instead of
+	// trusting a value that drifts on reconnect or across timezones.
+	ServerNow int64 `json:"serverNow"`
 }
 
 // OutgoingMessage represents a message in the chatroom.
@@ -191,6 +351,29 @@ type Ready struct {
 	WordHints          []*WordHint `json:"wordHints"`
 	Players            []*Player   `json:"players"`
 	CurrentDrawing     []any       `json:"currentDrawing"`
+	// ServerNow is the server's current unix time in milliseconds, sent
+	// alongside RoundEndTime so that clients can compute the remaining time
+	// themselves instead of trusting a value that drifts on reconnect or
+	// across timezones.
+	ServerNow int64 `json:"serverNow"`
+	// Spectators are the players currently observing the lobby without
+	// being scheduled as drawers.
+	Spectators []*Player `json:"spectators"`
+	// DrawingSnapshot carries the delta-compressed drawing history, and is
+	// only populated for clients that negotiated support for it via
+	// DrawingSnapshotQueryParam; see EventTypeDrawingSnapshot. Clients that
+	// didn't negotiate it get the same history decoded into CurrentDrawing
+	// instead.
+	DrawingSnapshot *DrawingSnapshot `json:"drawingSnapshot,omitempty"`
+}
+
+// SpectatorReady is the bootstrap message sent to a spectator upon
+// connecting. It is Ready-like on purpose, replaying the exact same state a
+// reconnecting player would receive, so that a late observer sees the
+// current canvas, wordhints and scores without requiring any further
+// catch-up logic.
+type SpectatorReady struct {
+	*Ready
 }
 
 // Player represents a participant in a Lobby.
@@ -228,6 +411,47 @@ type Player struct {
 	LastScore int         `json:"lastScore"`
 	Rank      int         `json:"rank"`
 	State     PlayerState `json:"state"`
+
+	// reconnectToken is handed to the player at first join and must be
+	// presented over the websocket query-string to resume this exact
+	// Player slot after a crash or page close.
+	reconnectToken string
+	// lastSeen is set when the player's reconnectToken is issued, see
+	// Lobby.IssueReconnectTokenFor. It isn't updated on later connections
+	// and isn't consulted when evicting players.
+	lastSeen time.Time
+
+	// turnsAsDrawer counts how many turns this player has drawn, used to
+	// compute their average drawingSpeed tag at game over.
+	turnsAsDrawer int
+	// totalDrawingDurationMs accumulates the time taken across all turns
+	// this player has drawn, in milliseconds.
+	totalDrawingDurationMs int64
+	// correctGuessCount counts how many turns this player guessed
+	// correctly in.
+	correctGuessCount int
+	// totalGuessDurationMs accumulates the time-to-guess across all turns
+	// this player guessed correctly in, in milliseconds.
+	totalGuessDurationMs int64
+	// closeGuessCount counts how many close, but incorrect, guesses this
+	// player has made across the game.
+	closeGuessCount int
+	// undoCount counts how many times this player has used undo while
+	// drawing, across the game.
+	undoCount int
+}
+
+// GameInfo is a stable, persistable summary of a Lobby, suitable for
+// listing on a lobby-discovery endpoint without exposing anything a
+// player's client wouldn't already be able to see.
+type GameInfo struct {
+	ID          string   `json:"id"`
+	Status      State    `json:"status"`
+	PlayerNames []string `json:"playerNames"`
+	CreatedAt   int64    `json:"createdAt"`
+	Round       int      `json:"round"`
+	Rounds      int      `json:"rounds"`
+	HasPassword bool     `json:"hasPassword"`
 }
 
 // EditableLobbySettings represents all lobby settings that are editable by
@@ -256,4 +480,60 @@ type EditableLobbySettings struct {
 	// Rounds defines how many iterations a lobby does before the game ends.
 	// One iteration means every participant does one drawing.
 	Rounds int `json:"rounds"`
+	// BotSlots defines how many of the MaxPlayers slots may be occupied by
+	// bots instead of human players.
+	BotSlots int `json:"botSlots"`
+	// BotEndpoints are the HTTP callback URLs of the bots that have been
+	// registered for this lobby, in the order they were registered.
+	BotEndpoints []string `json:"botEndpoints"`
+}
+
+// BotPlayer represents a bot participant in a Lobby. Unlike Player, a
+// BotPlayer isn't reached via a websocket connection, but via an HTTP
+// callback that the lobby invokes whenever the bot needs to act.
+type BotPlayer struct {
+	// ID uniquely identifies the BotPlayer, the same way Player.ID does.
+	ID uuid.UUID `json:"id"`
+	// Name is the bot's displayed name.
+	Name string `json:"name"`
+	// Endpoint is the base URL the lobby posts drawing and guessing
+	// requests to.
+	Endpoint string `json:"endpoint"`
+	// AuthToken is sent as a bearer token on every callback, so that the
+	// bot can verify the request actually originates from this server.
+	AuthToken string `json:"-"`
+	// Cadence is the minimum amount of time the scheduler waits between
+	// replaying two consecutive line or fill events received from the bot's
+	// drawing response.
+	Cadence time.Duration `json:"cadence"`
+}
+
+// BotDrawRequest is the payload posted to a BotPlayer's Endpoint when it is
+// the bot's turn to draw.
+type BotDrawRequest struct {
+	Word   string `json:"word"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// BotDrawResponse is the expected response to a BotDrawRequest. Events are
+// decoded into their concrete LineEvent/FillEvent type based on each
+// element's "type" field, see decodeBotEvent, then replayed by the
+// scheduler at the BotPlayer's configured Cadence, in order.
+type BotDrawResponse struct {
+	Events []json.RawMessage `json:"events"`
+}
+
+// BotGuessRequest is the payload posted to a BotPlayer's Endpoint whenever
+// another player is drawing and new information becomes available, such as
+// an updated WordHint or a newly drawn line or fill.
+type BotGuessRequest struct {
+	WordHints []*WordHint `json:"wordHints"`
+	Events    []any       `json:"events"`
+}
+
+// BotGuessResponse is the expected response to a BotGuessRequest. An empty
+// Guess means the bot has chosen not to guess yet.
+type BotGuessResponse struct {
+	Guess string `json:"guess"`
 }
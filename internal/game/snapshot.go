@@ -0,0 +1,257 @@
+package game
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// snapshotRollInterval is how many strokes the rolling snapshot batches
+// together before the history starts a fresh tail of raw events.
+const snapshotRollInterval = 50
+
+// fixedPointScale converts a float32 canvas coordinate into a 16-bit
+// fixed-point integer with one decimal digit of precision.
+const fixedPointScale = 10
+
+// EncodeDrawingSnapshot compresses a run of line strokes into a
+// DrawingSnapshot. The first stroke's origin is stored as absolute 16-bit
+// fixed-point coordinates; every following point is stored as a varint
+// signed delta against the previous point. Color and line width are
+// run-length encoded, since a drawer typically keeps both constant across
+// many consecutive strokes.
+func EncodeDrawingSnapshot(strokes []LineEvent) (*DrawingSnapshot, error) {
+	if len(strokes) == 0 {
+		return &DrawingSnapshot{}, nil
+	}
+
+	buffer := new(bytes.Buffer)
+	varintBuf := make([]byte, binary.MaxVarintLen64)
+
+	writeVarint := func(value int64) {
+		n := binary.PutVarint(varintBuf, value)
+		buffer.Write(varintBuf[:n])
+	}
+
+	var prevToX, prevToY int16
+	haveOrigin := false
+
+	writeRunHeader := func(runLength int, color RGBColor, lineWidth float32) error {
+		writeVarint(int64(runLength))
+		if err := buffer.WriteByte(color.R); err != nil {
+			return err
+		}
+		if err := buffer.WriteByte(color.G); err != nil {
+			return err
+		}
+		if err := buffer.WriteByte(color.B); err != nil {
+			return err
+		}
+		writeVarint(int64(lineWidth * fixedPointScale))
+		return nil
+	}
+
+	writeStrokeCoords := func(stroke LineEvent) {
+		fromX := int16(stroke.Data.FromX * fixedPointScale)
+		fromY := int16(stroke.Data.FromY * fixedPointScale)
+		toX := int16(stroke.Data.ToX * fixedPointScale)
+		toY := int16(stroke.Data.ToY * fixedPointScale)
+
+		if !haveOrigin {
+			writeVarint(int64(fromX))
+			writeVarint(int64(fromY))
+			haveOrigin = true
+		} else {
+			writeVarint(int64(fromX - prevToX))
+			writeVarint(int64(fromY - prevToY))
+		}
+		writeVarint(int64(toX - fromX))
+		writeVarint(int64(toY - fromY))
+		prevToX, prevToY = toX, toY
+	}
+
+	runStart := 0
+	for i := 1; i <= len(strokes); i++ {
+		if i < len(strokes) &&
+			strokes[i].Data.Color == strokes[runStart].Data.Color &&
+			strokes[i].Data.LineWidth == strokes[runStart].Data.LineWidth {
+			continue
+		}
+
+		run := strokes[runStart:i]
+		if err := writeRunHeader(len(run), run[0].Data.Color, run[0].Data.LineWidth); err != nil {
+			return nil, fmt.Errorf("writing run header: %w", err)
+		}
+		for _, stroke := range run {
+			writeStrokeCoords(stroke)
+		}
+		runStart = i
+	}
+
+	return &DrawingSnapshot{
+		StrokeCount: len(strokes),
+		Frame:       buffer.Bytes(),
+	}, nil
+}
+
+// DecodeDrawingSnapshot reverses EncodeDrawingSnapshot, reconstructing the
+// original LineEvents. The reconstructed events carry EventTypeLine as
+// their Type.
+func DecodeDrawingSnapshot(snapshot *DrawingSnapshot) ([]LineEvent, error) {
+	if snapshot.StrokeCount == 0 {
+		return nil, nil
+	}
+
+	reader := bytes.NewReader(snapshot.Frame)
+
+	readVarint := func() (int64, error) {
+		value, err := binary.ReadVarint(reader)
+		if err != nil {
+			return 0, fmt.Errorf("reading varint: %w", err)
+		}
+		return value, nil
+	}
+
+	strokes := make([]LineEvent, 0, snapshot.StrokeCount)
+
+	var prevToX, prevToY int16
+	haveOrigin := false
+	decoded := 0
+
+	for decoded < snapshot.StrokeCount {
+		runLength, err := readVarint()
+		if err != nil {
+			return nil, fmt.Errorf("reading run length: %w", err)
+		}
+		r, err := reader.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("reading run color: %w", err)
+		}
+		g, err := reader.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("reading run color: %w", err)
+		}
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("reading run color: %w", err)
+		}
+		lineWidthFixed, err := readVarint()
+		if err != nil {
+			return nil, fmt.Errorf("reading run line width: %w", err)
+		}
+		color := RGBColor{R: r, G: g, B: b}
+		lineWidth := float32(lineWidthFixed) / fixedPointScale
+
+		for run := int64(0); run < runLength; run++ {
+			var fromX, fromY int16
+			if !haveOrigin {
+				v, err := readVarint()
+				if err != nil {
+					return nil, err
+				}
+				fromX = int16(v)
+				v, err = readVarint()
+				if err != nil {
+					return nil, err
+				}
+				fromY = int16(v)
+				haveOrigin = true
+			} else {
+				dx, err := readVarint()
+				if err != nil {
+					return nil, err
+				}
+				dy, err := readVarint()
+				if err != nil {
+					return nil, err
+				}
+				fromX = prevToX + int16(dx)
+				fromY = prevToY + int16(dy)
+			}
+
+			dx, err := readVarint()
+			if err != nil {
+				return nil, err
+			}
+			dy, err := readVarint()
+			if err != nil {
+				return nil, err
+			}
+			toX := fromX + int16(dx)
+			toY := fromY + int16(dy)
+			prevToX, prevToY = toX, toY
+
+			stroke := LineEvent{Type: EventTypeLine}
+			stroke.Data.FromX = float32(fromX) / fixedPointScale
+			stroke.Data.FromY = float32(fromY) / fixedPointScale
+			stroke.Data.ToX = float32(toX) / fixedPointScale
+			stroke.Data.ToY = float32(toY) / fixedPointScale
+			stroke.Data.Color = color
+			stroke.Data.LineWidth = lineWidth
+			strokes = append(strokes, stroke)
+			decoded++
+		}
+	}
+
+	return strokes, nil
+}
+
+// DrawingHistory tracks the raw strokes drawn since the last clear and
+// rolls them up into a DrawingSnapshot every snapshotRollInterval strokes,
+// so that new joiners only need the latest snapshot plus the raw tail since
+// it was taken, instead of the full stroke-by-stroke log. Fill events have
+// no compressed representation, since they're rare compared to line
+// strokes, so they're tracked separately as a plain, uncompressed list.
+type DrawingHistory struct {
+	snapshot *DrawingSnapshot
+	tail     []LineEvent
+	fills    []FillEvent
+}
+
+// Append records a newly drawn stroke, rolling it into a fresh snapshot
+// once the tail reaches snapshotRollInterval entries.
+func (history *DrawingHistory) Append(stroke LineEvent) error {
+	history.tail = append(history.tail, stroke)
+	if len(history.tail) < snapshotRollInterval {
+		return nil
+	}
+
+	strokes := history.tail
+	if history.snapshot != nil {
+		previous, err := DecodeDrawingSnapshot(history.snapshot)
+		if err != nil {
+			return fmt.Errorf("decoding previous snapshot for roll-up: %w", err)
+		}
+		strokes = append(previous, strokes...)
+	}
+
+	snapshot, err := EncodeDrawingSnapshot(strokes)
+	if err != nil {
+		return fmt.Errorf("encoding rolled-up snapshot: %w", err)
+	}
+
+	history.snapshot = snapshot
+	history.tail = nil
+	return nil
+}
+
+// AppendFill records a bucket-fill event, so it can be replayed to a new
+// joiner by Bootstrap alongside the rolling line snapshot and tail.
+func (history *DrawingHistory) AppendFill(fill FillEvent) {
+	history.fills = append(history.fills, fill)
+}
+
+// Clear discards all recorded strokes and fills, e.g. when the drawing
+// board has been cleared or a new turn has started.
+func (history *DrawingHistory) Clear() {
+	history.snapshot = nil
+	history.tail = nil
+	history.fills = nil
+}
+
+// Bootstrap returns the data a new joiner needs to reconstruct the current
+// drawing: the latest rolling snapshot, if any, the raw tail of strokes
+// recorded since, and every fill recorded since the last clear.
+func (history *DrawingHistory) Bootstrap() (*DrawingSnapshot, []LineEvent, []FillEvent) {
+	return history.snapshot, history.tail, history.fills
+}
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomLineEvent(r *rand.Rand) LineEvent {
+	coord := func() float32 {
+		return float32(r.Intn(20000)-10000) / fixedPointScale
+	}
+
+	var event LineEvent
+	event.Type = EventTypeLine
+	event.Data.FromX = coord()
+	event.Data.FromY = coord()
+	event.Data.ToX = coord()
+	event.Data.ToY = coord()
+	event.Data.Color = RGBColor{R: uint8(r.Intn(256)), G: uint8(r.Intn(256)), B: uint8(r.Intn(256))}
+	event.Data.LineWidth = float32(r.Intn(200)) / fixedPointScale
+	return event
+}
+
+func TestEncodeDecodeDrawingSnapshotRoundtrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		strokeCount := r.Intn(80) + 1
+		strokes := make([]LineEvent, strokeCount)
+		for j := range strokes {
+			strokes[j] = randomLineEvent(r)
+		}
+
+		snapshot, err := EncodeDrawingSnapshot(strokes)
+		if err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		if snapshot.StrokeCount != strokeCount {
+			t.Fatalf("got StrokeCount %d, want %d", snapshot.StrokeCount, strokeCount)
+		}
+
+		decoded, err := DecodeDrawingSnapshot(snapshot)
+		if err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(decoded) != len(strokes) {
+			t.Fatalf("got %d decoded strokes, want %d", len(decoded), len(strokes))
+		}
+
+		for j := range strokes {
+			if decoded[j] != strokes[j] {
+				t.Fatalf("stroke %d mismatch: got %+v, want %+v", j, decoded[j], strokes[j])
+			}
+		}
+	}
+}
+
+func TestEncodeDrawingSnapshotEmpty(t *testing.T) {
+	snapshot, err := EncodeDrawingSnapshot(nil)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if snapshot.StrokeCount != 0 {
+		t.Fatalf("got StrokeCount %d, want 0", snapshot.StrokeCount)
+	}
+
+	decoded, err := DecodeDrawingSnapshot(snapshot)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(decoded) != 0 {
+		t.Fatalf("got %d strokes, want 0", len(decoded))
+	}
+}
+
+func TestDrawingHistoryBootstrap(t *testing.T) {
+	var history DrawingHistory
+
+	var strokes []LineEvent
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < snapshotRollInterval+5; i++ {
+		stroke := randomLineEvent(r)
+		strokes = append(strokes, stroke)
+		if err := history.Append(stroke); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	snapshot, tail, fills := history.Bootstrap()
+	if snapshot == nil {
+		t.Fatal("expected a rolled-up snapshot after exceeding snapshotRollInterval strokes")
+	}
+	if len(tail) != 5 {
+		t.Fatalf("got tail length %d, want 5", len(tail))
+	}
+	if len(fills) != 0 {
+		t.Fatalf("got %d fills, want 0", len(fills))
+	}
+
+	decodedSnapshot, err := DecodeDrawingSnapshot(snapshot)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	replayed := append(decodedSnapshot, tail...)
+	if len(replayed) != len(strokes) {
+		t.Fatalf("got %d replayed strokes, want %d", len(replayed), len(strokes))
+	}
+	for i := range strokes {
+		if replayed[i] != strokes[i] {
+			t.Fatalf("stroke %d mismatch after bootstrap replay: got %+v, want %+v", i, replayed[i], strokes[i])
+		}
+	}
+}
+
+func TestDrawingHistoryBootstrapIncludesFills(t *testing.T) {
+	var history DrawingHistory
+
+	fill := FillEvent{
+		Type: EventTypeFill,
+		Data: &struct {
+			X     float32  `json:"x"`
+			Y     float32  `json:"y"`
+			Color RGBColor `json:"color"`
+		}{X: 12, Y: 34, Color: RGBColor{R: 1, G: 2, B: 3}},
+	}
+	history.AppendFill(fill)
+
+	_, _, fills := history.Bootstrap()
+	if len(fills) != 1 {
+		t.Fatalf("got %d fills, want 1", len(fills))
+	}
+	if fills[0] != fill {
+		t.Fatalf("got fill %+v, want %+v", fills[0], fill)
+	}
+
+	history.Clear()
+	_, _, fills = history.Bootstrap()
+	if len(fills) != 0 {
+		t.Fatalf("got %d fills after Clear, want 0", len(fills))
+	}
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"sync"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// Spectator represents a websocket connection that observes a Lobby without
+// participating in it. Spectators are never scheduled as drawers and don't
+// count toward MaxPlayers or kick-vote quorums; they exist purely to watch.
+type Spectator struct {
+	ID               uuid.UUID
+	Name             string
+	ws               *websocket.Conn
+	socketMutex      *sync.Mutex
+	lastKnownAddress string
+}
+
+// NewSpectator creates a Spectator bound to the given websocket connection.
+func NewSpectator(id uuid.UUID, name string, ws *websocket.Conn, lastKnownAddress string) *Spectator {
+	return &Spectator{
+		ID:               id,
+		Name:             name,
+		ws:               ws,
+		socketMutex:      &sync.Mutex{},
+		lastKnownAddress: lastKnownAddress,
+	}
+}
+
+// WriteJSON sends data as a JSON encoded websocket message, guarding against
+// concurrent writes the same way Player's connection handling does.
+func (spectator *Spectator) WriteJSON(data any) error {
+	spectator.socketMutex.Lock()
+	defer spectator.socketMutex.Unlock()
+	return spectator.ws.WriteJSON(data)
+}
+
+// AsPlayer returns a read-only *Player view of the spectator, suitable for
+// inclusion in the Spectators field of Ready and NextTurn. The returned
+// Player is never scheduled and never counted towards MaxPlayers; it merely
+// reuses the existing wire format for display purposes.
+func (spectator *Spectator) AsPlayer() *Player {
+	return &Player{
+		ID:        spectator.ID,
+		Name:      spectator.Name,
+		Connected: true,
+	}
+}
@@ -0,0 +1,50 @@
+package game
+
+import "time"
+
+// timeSyncInterval is how often EventTypeTimeSync heartbeats are pushed to
+// clients while a deadline is active.
+const timeSyncInterval = 5 * time.Second
+
+// TimeSyncTicker periodically invokes onTick with a TimeSyncEvent describing
+// the given phase and deadline, until Stop is called. The caller is expected
+// to broadcast the event passed to onTick the same way any other outgoing
+// event is broadcast.
+type TimeSyncTicker struct {
+	ticker *time.Ticker
+	stop   chan struct{}
+}
+
+// StartTimeSync begins pushing a TimeSyncEvent for the given phase and
+// deadline (unix ms) every timeSyncInterval, until the returned ticker is
+// stopped. This runs on its own goroutine and never blocks the caller.
+func StartTimeSync(phase TimeSyncPhase, deadline int64, onTick func(event *TimeSyncEvent)) *TimeSyncTicker {
+	t := &TimeSyncTicker{
+		ticker: time.NewTicker(timeSyncInterval),
+		stop:   make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-t.ticker.C:
+				onTick(&TimeSyncEvent{
+					ServerNow: time.Now().UnixMilli(),
+					Deadline:  deadline,
+					Phase:     phase,
+				})
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+
+	return t
+}
+
+// Stop halts the ticker. It must be called once the associated phase ends,
+// typically when the next turn starts or a word has been chosen.
+func (t *TimeSyncTicker) Stop() {
+	t.ticker.Stop()
+	close(t.stop)
+}
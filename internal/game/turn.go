@@ -0,0 +1,225 @@
+package game
+
+import "time"
+
+// chooseWordDeadline and drawingDeadline live on Lobby itself; see the
+// fields added there. This file implements the phase transitions that
+// populate and broadcast them.
+
+// StartChooseWordPhase begins the word-selection phase: it sends the
+// drawer a YourTurn carrying the word options and the phase's own deadline,
+// and starts a TimeSyncEvent heartbeat for that deadline so clients can
+// resynchronize without replaying state. The deadline is only reported;
+// nothing here acts once it has passed.
+func (l *Lobby) StartChooseWordPhase(words []string, timeout time.Duration) {
+	l.mutex.Lock()
+	now := time.Now()
+	deadline := now.Add(timeout).UnixMilli()
+	l.chooseWordDeadline = deadline
+	l.restartTimeSyncLocked(TimeSyncPhaseChooseWord, deadline)
+	var drawer *Player
+	for _, player := range l.Players {
+		if player.ID == l.DrawerID {
+			drawer = player
+			break
+		}
+	}
+	l.mutex.Unlock()
+
+	if drawer != nil {
+		_ = drawer.WriteJSON(&Event{
+			Type: EventTypeYourTurn,
+			Data: &YourTurn{Words: words, Deadline: deadline, ServerNow: now.UnixMilli()},
+		})
+	}
+}
+
+// StartDrawingPhase begins the drawing phase for word: it broadcasts a
+// NextTurn with an absolute RoundEndTime and the server's current time,
+// restarts the TimeSyncEvent heartbeat for the new deadline, and kicks off
+// the bot scheduler if the drawer is a BotPlayer rather than a human.
+func (l *Lobby) StartDrawingPhase(previousWord, word string, timeout time.Duration) {
+	l.mutex.Lock()
+	l.CurrentWord = word
+	l.drawingHistory.Clear()
+	now := time.Now()
+	deadline := now.Add(timeout).UnixMilli()
+	l.drawingDeadline = deadline
+	l.turnStartedAt = now.UnixMilli()
+	l.restartTimeSyncLocked(TimeSyncPhaseDrawing, deadline)
+	drawingBot := l.botByIDLocked(l.DrawerID)
+	nextTurn := &NextTurn{
+		PreviousWord: previousWord,
+		Players:      l.Players,
+		Round:        l.Round,
+		RoundEndTime: int(deadline / 1000),
+		ServerNow:    now.UnixMilli(),
+		Spectators:   spectatorsAsPlayers(l.Spectators),
+	}
+	l.mutex.Unlock()
+
+	l.Broadcast(&Event{Type: EventTypeNextTurn, Data: nextTurn})
+
+	if drawingBot != nil {
+		l.StartBotDrawingTurn(drawingBot, word, defaultBotCanvasWidth, defaultBotCanvasHeight)
+	}
+}
+
+// EndTurn stops the current phase's TimeSyncEvent heartbeat. It must be
+// called once a turn ends, whether because the drawing time ran out or
+// everyone guessed correctly.
+func (l *Lobby) EndTurn() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.stopTimeSyncLocked()
+}
+
+// FinishTurn ends the current drawing turn: it builds a TurnReviewEvent out
+// of word, drawer, guesses and undoCount, records it for the eventual
+// GameReview built by BuildGameOver, broadcasts it so clients can show a
+// post-turn summary, and stops the drawing phase's TimeSyncEvent heartbeat.
+func (l *Lobby) FinishTurn(word string, drawer *Player, guesses []GuessRecord, undoCount int) *TurnReviewEvent {
+	l.mutex.Lock()
+	durationMs := time.Now().UnixMilli() - l.turnStartedAt
+	review := BuildTurnReview(word, drawer, durationMs, guesses, undoCount)
+	l.turnReviews = append(l.turnReviews, review)
+	l.mutex.Unlock()
+
+	l.EndTurn()
+	l.Broadcast(&Event{Type: EventTypeTurnReview, Data: review})
+	return review
+}
+
+// restartTimeSyncLocked stops any previous heartbeat and starts a new one
+// for phase/deadline. l.mutex must be held.
+func (l *Lobby) restartTimeSyncLocked(phase TimeSyncPhase, deadline int64) {
+	l.stopTimeSyncLocked()
+	l.timeSync = StartTimeSync(phase, deadline, func(event *TimeSyncEvent) {
+		l.Broadcast(&Event{Type: EventTypeTimeSync, Data: event})
+	})
+}
+
+// stopTimeSyncLocked stops the current heartbeat, if any. l.mutex must be
+// held.
+func (l *Lobby) stopTimeSyncLocked() {
+	if l.timeSync != nil {
+		l.timeSync.Stop()
+		l.timeSync = nil
+	}
+}
+
+// BuildReady returns the Ready bootstrap message for player. Its
+// RoundEndTime is always the latest drawing deadline, also while a word is
+// still being chosen. supportsDrawingSnapshot indicates
+// whether player's connection negotiated EventTypeDrawingSnapshot support,
+// see DrawingSnapshotQueryParam.
+func (l *Lobby) BuildReady(player *Player, supportsDrawingSnapshot bool) *Ready {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	ready := l.buildReadyLocked(supportsDrawingSnapshot)
+	ready.PlayerID = player.ID
+	ready.PlayerName = player.Name
+	ready.AllowDrawing = player.ID == l.DrawerID
+	return ready
+}
+
+// BuildSpectatorReady returns the bootstrap message for a newly connected
+// spectator. It is Ready-like on purpose, replaying the exact same
+// wordhints, players, scores and drawing a reconnecting player would see,
+// so a late observer sees the current game state without any further
+// catch-up logic. supportsDrawingSnapshot is as in BuildReady.
+func (l *Lobby) BuildSpectatorReady(spectator *Spectator, supportsDrawingSnapshot bool) *SpectatorReady {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	ready := l.buildReadyLocked(supportsDrawingSnapshot)
+	ready.PlayerID = spectator.ID
+	ready.PlayerName = spectator.Name
+	return &SpectatorReady{Ready: ready}
+}
+
+// buildReadyLocked constructs the state shared by Ready and SpectatorReady,
+// including the current drawing: as a DrawingSnapshot for clients that
+// negotiated support for it, or decoded into raw CurrentDrawing events for
+// clients that didn't. l.mutex must be held.
+func (l *Lobby) buildReadyLocked(supportsDrawingSnapshot bool) *Ready {
+	ready := &Ready{
+		VotekickEnabled:    l.EnableVotekick,
+		GameState:          l.State,
+		OwnerID:            l.OwnerID,
+		Round:              l.Round,
+		Rounds:             l.Rounds,
+		RoundEndTime:       int(l.drawingDeadline / 1000),
+		DrawingTimeSetting: l.DrawingTime,
+		WordHints:          l.WordHints,
+		Players:            l.Players,
+		ServerNow:          time.Now().UnixMilli(),
+		Spectators:         spectatorsAsPlayers(l.Spectators),
+	}
+
+	snapshot, tail, fills := l.drawingHistory.Bootstrap()
+	if supportsDrawingSnapshot {
+		ready.DrawingSnapshot = snapshot
+		ready.CurrentDrawing = append(linesAsEvents(tail), fillsAsEvents(fills)...)
+		return ready
+	}
+
+	var events []any
+	if snapshot != nil {
+		if decoded, err := DecodeDrawingSnapshot(snapshot); err == nil {
+			events = append(events, linesAsEvents(decoded)...)
+		}
+	}
+	events = append(events, linesAsEvents(tail)...)
+	ready.CurrentDrawing = append(events, fillsAsEvents(fills)...)
+	return ready
+}
+
+// BuildGameOver returns the GameOverEvent bootstrap sent once the max round
+// limit has been reached: the final Ready-equivalent state, the last chosen
+// word, and the GameReview aggregated from every TurnReviewEvent collected
+// over the game via FinishTurn. supportsDrawingSnapshot is as in BuildReady.
+func (l *Lobby) BuildGameOver(previousWord string, supportsDrawingSnapshot bool) *GameOverEvent {
+	l.mutex.Lock()
+	ready := l.buildReadyLocked(supportsDrawingSnapshot)
+	review := BuildGameReview(l.turnReviews, l.Players)
+	l.mutex.Unlock()
+
+	return &GameOverEvent{
+		Ready:        ready,
+		PreviousWord: previousWord,
+		Review:       review,
+	}
+}
+
+// linesAsEvents widens a slice of LineEvent into the []any shape
+// CurrentDrawing is sent as.
+func linesAsEvents(lines []LineEvent) []any {
+	events := make([]any, 0, len(lines))
+	for _, line := range lines {
+		events = append(events, line)
+	}
+	return events
+}
+
+// fillsAsEvents widens a slice of FillEvent into the []any shape
+// CurrentDrawing is sent as.
+func fillsAsEvents(fills []FillEvent) []any {
+	events := make([]any, 0, len(fills))
+	for _, fill := range fills {
+		events = append(events, fill)
+	}
+	return events
+}
+
+// spectatorsAsPlayers converts spectators into their read-only Player view,
+// see Spectator.AsPlayer, for inclusion in the Spectators field of Ready and
+// NextTurn.
+func spectatorsAsPlayers(spectators []*Spectator) []*Player {
+	players := make([]*Player, 0, len(spectators))
+	for _, spectator := range spectators {
+		players = append(players, spectator.AsPlayer())
+	}
+	return players
+}